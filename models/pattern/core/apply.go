@@ -0,0 +1,329 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshkit/models/oam/core/v1alpha1"
+)
+
+// LastAppliedPatternAnnotation is the annotation key under which the last
+// successfully applied pattern snapshot for a service is stored on the OAM
+// Component generated by GetApplicationComponentForApply, mirroring
+// "kubectl.kubernetes.io/last-applied-configuration" in `kubectl apply`.
+// The generated OAM Configuration does not carry this annotation.
+const LastAppliedPatternAnnotation = "meshery.io/last-applied-pattern"
+
+// ApplyOpType identifies the kind of action an ApplyOp represents.
+type ApplyOpType string
+
+const (
+	// ApplyOpCreate indicates the service does not yet exist live and
+	// should be created.
+	ApplyOpCreate ApplyOpType = "create"
+	// ApplyOpPatch indicates the service exists live and should be
+	// patched to reconcile pattern-driven changes.
+	ApplyOpPatch ApplyOpType = "patch"
+	// ApplyOpDelete indicates the service was removed from the pattern
+	// and its live component should be deleted.
+	ApplyOpDelete ApplyOpType = "delete"
+)
+
+// JSONPatchOp is a single RFC 6902 JSON Patch operation.
+type JSONPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ApplyOp is a single action the deployer should execute in order to
+// reconcile the live cluster/OAM state with a new pattern.
+type ApplyOp struct {
+	Type          ApplyOpType         `json:"type"`
+	ComponentName string              `json:"componentName"`
+	Component     *v1alpha1.Component `json:"component,omitempty"`
+	Patch         []JSONPatchOp       `json:"patch,omitempty"`
+}
+
+// stampLastApplied serializes svc as the canonical last-applied snapshot
+// and attaches it to comp's annotations.
+func stampLastApplied(comp *v1alpha1.Component, svc *Service) error {
+	snapshot, err := json.Marshal(svc)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot service %s for last-applied annotation: %s", comp.Name, err)
+	}
+
+	if comp.ObjectMeta.Annotations == nil {
+		comp.ObjectMeta.Annotations = map[string]string{}
+	}
+	comp.ObjectMeta.Annotations[LastAppliedPatternAnnotation] = string(snapshot)
+
+	return nil
+}
+
+// GetApplicationComponentForApply is like GetApplicationComponent but also
+// stamps the component with a `meshery.io/last-applied-pattern` annotation
+// capturing a snapshot of the service as applied, so a subsequent apply can
+// three-way merge against it.
+func (p *Pattern) GetApplicationComponentForApply(name string) (v1alpha1.Component, error) {
+	comp, err := p.GetApplicationComponent(name)
+	if err != nil {
+		return comp, err
+	}
+
+	svc, ok := p.Services[name]
+	if !ok {
+		return comp, fmt.Errorf("invalid service name")
+	}
+
+	if err := stampLastApplied(&comp, svc); err != nil {
+		return comp, err
+	}
+
+	return comp, nil
+}
+
+// lastAppliedService extracts the Service snapshot recorded on comp by a
+// prior GetApplicationComponentForApply call, if any.
+func lastAppliedService(comp v1alpha1.Component) (*Service, bool) {
+	raw, ok := comp.ObjectMeta.Annotations[LastAppliedPatternAnnotation]
+	if !ok || raw == "" {
+		return nil, false
+	}
+
+	var svc Service
+	if err := json.Unmarshal([]byte(raw), &svc); err != nil {
+		return nil, false
+	}
+
+	return &svc, true
+}
+
+// BuildApplyPatches computes a three-way merge between (a) the last-applied
+// snapshot (prev), (b) the current cluster/OAM state (live), and (c) the
+// new pattern (p, the receiver), and returns the create/patch/delete
+// operations a deployer should execute, in dependency order, to reconcile
+// live with p while preserving user-edited fields that the pattern never
+// touched. Creates (and patches) are ordered using p's own DeploymentPlan
+// wave order, so a dependent is never created before its dependency;
+// deletes are ordered using prev's DeploymentPlan in reverse, so a
+// service is torn down only after whatever still depends on it.
+func (p *Pattern) BuildApplyPatches(prev *Pattern, live map[string]v1alpha1.Component) ([]ApplyOp, error) {
+	var ops []ApplyOp
+
+	var creates, patches, deletes []ApplyOp
+
+	waves, err := p.DeploymentPlan()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute a dependency order for apply: %s", err)
+	}
+
+	var orderedNames []string
+	for _, wave := range waves {
+		orderedNames = append(orderedNames, wave...)
+	}
+
+	for _, name := range orderedNames {
+		svc := p.Services[name]
+
+		liveComp, isLive := live[name]
+		if !isLive {
+			comp, err := p.GetApplicationComponentForApply(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build create op for service %s: %s", name, err)
+			}
+			creates = append(creates, ApplyOp{
+				Type:          ApplyOpCreate,
+				ComponentName: name,
+				Component:     &comp,
+			})
+			continue
+		}
+
+		var baseSvc *Service
+		if prev != nil {
+			baseSvc = prev.Services[name]
+		}
+		if baseSvc == nil {
+			if s, ok := lastAppliedService(liveComp); ok {
+				baseSvc = s
+			} else {
+				baseSvc = &Service{}
+			}
+		}
+
+		liveSettings := liveComp.Spec.Settings
+
+		patchOps := buildServicePatch(baseSvc, liveSettings, svc, liveComp)
+		if len(patchOps) > 0 {
+			patches = append(patches, ApplyOp{
+				Type:          ApplyOpPatch,
+				ComponentName: name,
+				Patch:         patchOps,
+			})
+		}
+	}
+
+	if prev != nil {
+		prevWaves, err := prev.DeploymentPlan()
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute a teardown order for apply: %s", err)
+		}
+
+		var reverseOrder []string
+		for i := len(prevWaves) - 1; i >= 0; i-- {
+			reverseOrder = append(reverseOrder, prevWaves[i]...)
+		}
+
+		for _, name := range reverseOrder {
+			if _, stillWanted := p.Services[name]; stillWanted {
+				continue
+			}
+			if _, isLive := live[name]; !isLive {
+				// already gone, nothing to delete
+				continue
+			}
+			deletes = append(deletes, ApplyOp{
+				Type:          ApplyOpDelete,
+				ComponentName: name,
+			})
+		}
+	}
+
+	ops = append(ops, creates...)
+	ops = append(ops, patches...)
+	ops = append(ops, deletes...)
+
+	return ops, nil
+}
+
+// buildServicePatch three-way merges base (last-applied), live (current
+// cluster state) and newSvc (the incoming pattern) and returns the JSON
+// Patch operations needed to move liveComp to the merged result.
+func buildServicePatch(base *Service, liveSettings map[string]interface{}, newSvc *Service, liveComp v1alpha1.Component) []JSONPatchOp {
+	var ops []JSONPatchOp
+
+	if newSvc.Type != base.Type && newSvc.Type != liveComp.Spec.Type {
+		ops = append(ops, JSONPatchOp{Op: "replace", Path: "/spec/type", Value: newSvc.Type})
+	}
+	if newSvc.Namespace != base.Namespace && newSvc.Namespace != liveComp.ObjectMeta.Namespace {
+		ops = append(ops, JSONPatchOp{Op: "replace", Path: "/metadata/namespace", Value: newSvc.Namespace})
+	}
+
+	merged := threeWayMergeMap(base.Settings, liveSettings, newSvc.Settings)
+	ops = append(ops, diffToJSONPatch("/spec/settings", liveSettings, merged)...)
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Path < ops[j].Path })
+
+	return ops
+}
+
+// threeWayMergeMap merges base, live and newm: fields the pattern changed
+// relative to base win (pattern wins); fields the pattern left untouched
+// keep whatever is live (user edit wins), except that such a field missing
+// live is restored from the pattern; fields the pattern removed relative to
+// base are dropped, unless their live value has diverged from base, in
+// which case the live value is kept.
+func threeWayMergeMap(base, live, newm map[string]interface{}) map[string]interface{} {
+	keys := map[string]bool{}
+	for k := range base {
+		keys[k] = true
+	}
+	for k := range live {
+		keys[k] = true
+	}
+	for k := range newm {
+		keys[k] = true
+	}
+
+	result := map[string]interface{}{}
+
+	for k := range keys {
+		bv, bok := base[k]
+		lv, lok := live[k]
+		nv, nok := newm[k]
+
+		bMap, bIsMap := bv.(map[string]interface{})
+		lMap, lIsMap := lv.(map[string]interface{})
+		nMap, nIsMap := nv.(map[string]interface{})
+
+		if (bIsMap || !bok) && (lIsMap || !lok) && (nIsMap || !nok) && (bIsMap || lIsMap || nIsMap) {
+			sub := threeWayMergeMap(bMap, lMap, nMap)
+			if len(sub) > 0 {
+				result[k] = sub
+			}
+			continue
+		}
+
+		switch {
+		case nok && !valuesEqual(nv, bv):
+			// Pattern changed this field: pattern wins.
+			result[k] = nv
+		case !nok && bok:
+			// Pattern dropped a field it used to own: drop it, unless the
+			// user has since diverged it live, in which case keep the edit.
+			if lok && !valuesEqual(lv, bv) {
+				result[k] = lv
+			}
+		case lok:
+			// Pattern never touched this field: preserve the live edit.
+			result[k] = lv
+		case nok:
+			result[k] = nv
+		}
+	}
+
+	return result
+}
+
+// diffToJSONPatch compares live and desired (both possibly nested
+// map[string]interface{} trees) and emits the JSON Patch operations,
+// rooted at basePath, needed to turn live into desired.
+func diffToJSONPatch(basePath string, live, desired map[string]interface{}) []JSONPatchOp {
+	var ops []JSONPatchOp
+
+	keys := map[string]bool{}
+	for k := range live {
+		keys[k] = true
+	}
+	for k := range desired {
+		keys[k] = true
+	}
+
+	for k := range keys {
+		path := basePath + "/" + jsonPointerEscape(k)
+
+		lv, lok := live[k]
+		dv, dok := desired[k]
+
+		if !dok {
+			ops = append(ops, JSONPatchOp{Op: "remove", Path: path})
+			continue
+		}
+		if !lok {
+			ops = append(ops, JSONPatchOp{Op: "add", Path: path, Value: dv})
+			continue
+		}
+
+		lMap, lIsMap := lv.(map[string]interface{})
+		dMap, dIsMap := dv.(map[string]interface{})
+		if lIsMap && dIsMap {
+			ops = append(ops, diffToJSONPatch(path, lMap, dMap)...)
+			continue
+		}
+
+		if !valuesEqual(lv, dv) {
+			ops = append(ops, JSONPatchOp{Op: "replace", Path: path, Value: dv})
+		}
+	}
+
+	return ops
+}
+
+func jsonPointerEscape(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	s = strings.ReplaceAll(s, "/", "~1")
+	return s
+}
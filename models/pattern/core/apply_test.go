@@ -0,0 +1,177 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/layer5io/meshkit/models/oam/core/v1alpha1"
+)
+
+func TestThreeWayMergeMapPatternWins(t *testing.T) {
+	base := map[string]interface{}{"replicas": float64(1)}
+	live := map[string]interface{}{"replicas": float64(1)}
+	newm := map[string]interface{}{"replicas": float64(3)}
+
+	merged := threeWayMergeMap(base, live, newm)
+
+	if merged["replicas"] != float64(3) {
+		t.Fatalf("expected pattern-changed field to win, got %v", merged["replicas"])
+	}
+}
+
+func TestThreeWayMergeMapUserEditWins(t *testing.T) {
+	base := map[string]interface{}{"replicas": float64(1)}
+	live := map[string]interface{}{"replicas": float64(5)} // user scaled it up live
+	newm := map[string]interface{}{"replicas": float64(1)} // pattern never touched this field
+
+	merged := threeWayMergeMap(base, live, newm)
+
+	if merged["replicas"] != float64(5) {
+		t.Fatalf("expected untouched field to preserve the live edit, got %v", merged["replicas"])
+	}
+}
+
+func TestThreeWayMergeMapRemovedFieldUserDivergedIsKept(t *testing.T) {
+	base := map[string]interface{}{"timeout": float64(30)}
+	live := map[string]interface{}{"timeout": float64(99)} // user changed it live
+	newm := map[string]interface{}{}                       // pattern no longer declares this field
+
+	merged := threeWayMergeMap(base, live, newm)
+
+	v, ok := merged["timeout"]
+	if !ok {
+		t.Fatalf("expected live-diverged field to be kept, not silently dropped")
+	}
+	if v != float64(99) {
+		t.Fatalf("expected the live edit (99) to be preserved, got %v", v)
+	}
+}
+
+func TestThreeWayMergeMapRemovedFieldUserUnchangedIsDropped(t *testing.T) {
+	base := map[string]interface{}{"timeout": float64(30)}
+	live := map[string]interface{}{"timeout": float64(30)} // user never touched it
+	newm := map[string]interface{}{}                       // pattern removed this field
+
+	merged := threeWayMergeMap(base, live, newm)
+
+	if _, ok := merged["timeout"]; ok {
+		t.Fatalf("expected field the pattern removed (and the user never diverged) to be dropped, got %v", merged["timeout"])
+	}
+}
+
+func TestDiffToJSONPatchEscapesPointerPath(t *testing.T) {
+	live := map[string]interface{}{}
+	desired := map[string]interface{}{"a/b~c": "value"}
+
+	ops := diffToJSONPatch("/spec/settings", live, desired)
+
+	if len(ops) != 1 {
+		t.Fatalf("expected exactly one patch op, got %d: %+v", len(ops), ops)
+	}
+
+	op := ops[0]
+	if op.Op != "add" {
+		t.Fatalf("expected an add op, got %q", op.Op)
+	}
+
+	const wantPath = "/spec/settings/a~1b~0c"
+	if op.Path != wantPath {
+		t.Fatalf("expected escaped path %q, got %q", wantPath, op.Path)
+	}
+	if op.Value != "value" {
+		t.Fatalf("expected value %q, got %v", "value", op.Value)
+	}
+}
+
+func TestDiffToJSONPatchRemoveAndReplace(t *testing.T) {
+	live := map[string]interface{}{"a": "1", "b": "2"}
+	desired := map[string]interface{}{"a": "1", "b": "3"}
+
+	ops := diffToJSONPatch("/spec/settings", live, desired)
+	if len(ops) != 1 || ops[0].Op != "replace" || ops[0].Path != "/spec/settings/b" || ops[0].Value != "3" {
+		t.Fatalf("expected a single replace op for b, got %+v", ops)
+	}
+
+	desired = map[string]interface{}{"a": "1"}
+	ops = diffToJSONPatch("/spec/settings", live, desired)
+	if len(ops) != 1 || ops[0].Op != "remove" || ops[0].Path != "/spec/settings/b" {
+		t.Fatalf("expected a single remove op for b, got %+v", ops)
+	}
+}
+
+func TestBuildApplyPatchesOrdersCreatesByDependency(t *testing.T) {
+	p := &Pattern{Name: "test", Services: map[string]*Service{
+		"app":   {Type: "WebService", DependsOn: []string{"zebra"}},
+		"zebra": {Type: "WebService"},
+	}}
+
+	ops, err := p.BuildApplyPatches(nil, map[string]v1alpha1.Component{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var appIdx, zebraIdx = -1, -1
+	for i, op := range ops {
+		switch op.ComponentName {
+		case "app":
+			appIdx = i
+		case "zebra":
+			zebraIdx = i
+		}
+	}
+
+	if appIdx == -1 || zebraIdx == -1 {
+		t.Fatalf("expected create ops for both app and zebra, got %+v", ops)
+	}
+	if zebraIdx >= appIdx {
+		t.Fatalf("expected zebra's create (its dependency) to precede app's, got order %+v", ops)
+	}
+}
+
+func TestBuildApplyPatchesClassifiesCreatePatchDelete(t *testing.T) {
+	prev := &Pattern{Name: "test", Services: map[string]*Service{
+		"existing": {Type: "WebService", Settings: map[string]interface{}{"replicas": float64(1)}},
+		"removed":  {Type: "WebService"},
+	}}
+
+	p := &Pattern{Name: "test", Services: map[string]*Service{
+		"existing": {Type: "WebService", Settings: map[string]interface{}{"replicas": float64(3)}},
+		"newsvc":   {Type: "WebService"},
+	}}
+
+	live := map[string]v1alpha1.Component{
+		"existing": {
+			Spec: v1alpha1.ComponentSpec{
+				Type:     "WebService",
+				Settings: map[string]interface{}{"replicas": float64(1)},
+			},
+		},
+		"removed": {
+			Spec: v1alpha1.ComponentSpec{Type: "WebService"},
+		},
+	}
+
+	ops, err := p.BuildApplyPatches(prev, live)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	byName := map[string]ApplyOp{}
+	for _, op := range ops {
+		byName[op.ComponentName] = op
+	}
+
+	newsvcOp, ok := byName["newsvc"]
+	if !ok || newsvcOp.Type != ApplyOpCreate {
+		t.Fatalf("expected a create op for newsvc, got %+v", byName)
+	}
+
+	existingOp, ok := byName["existing"]
+	if !ok || existingOp.Type != ApplyOpPatch {
+		t.Fatalf("expected a patch op for existing, got %+v", byName)
+	}
+
+	removedOp, ok := byName["removed"]
+	if !ok || removedOp.Type != ApplyOpDelete {
+		t.Fatalf("expected a delete op for removed, got %+v", byName)
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// canonicalCopy returns a deep copy of p with every DependsOn slice
+// sorted. Services keys and Settings/Traits keys need no explicit sorting
+// pass of their own, since both encoding/json and gopkg.in/yaml.v2 already
+// emit map keys in sorted order - but Settings/Traits are still deep
+// copied (not just re-referenced), so that mutating the canonical copy can
+// never reach back and corrupt p.
+func (p *Pattern) canonicalCopy() *Pattern {
+	c := &Pattern{
+		Name:      p.Name,
+		PatternID: p.PatternID,
+		Services:  make(map[string]*Service, len(p.Services)),
+	}
+
+	for name, svc := range p.Services {
+		dependsOn := append([]string{}, svc.DependsOn...)
+		sort.Strings(dependsOn)
+
+		c.Services[name] = &Service{
+			ID:        svc.ID,
+			Name:      svc.Name,
+			Type:      svc.Type,
+			Namespace: svc.Namespace,
+			DependsOn: dependsOn,
+			Settings:  deepCopyMap(svc.Settings),
+			Traits:    deepCopyMap(svc.Traits),
+		}
+	}
+
+	return c
+}
+
+// deepCopyMap recursively copies a map[string]interface{} tree so that the
+// result shares no mutable state (maps or slices) with m.
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = deepCopyValue(v)
+	}
+	return out
+}
+
+func deepCopyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(val)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, e := range val {
+			out[i] = deepCopyValue(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// ToCanonicalYAML renders the pattern as YAML with Services keys,
+// DependsOn entries, and Settings/Traits keys all in sorted order, so
+// that round-tripping the same pattern produces byte-identical output.
+func (p *Pattern) ToCanonicalYAML() ([]byte, error) {
+	return yaml.Marshal(p.canonicalCopy())
+}
+
+// ToCanonicalJSON renders the pattern as indented JSON with Services
+// keys, DependsOn entries, and Settings/Traits keys all in sorted order,
+// so that round-tripping the same pattern produces byte-identical output.
+func (p *Pattern) ToCanonicalJSON() ([]byte, error) {
+	return json.MarshalIndent(p.canonicalCopy(), "", "  ")
+}
+
+// Hash returns the hex-encoded SHA-256 digest of the pattern's canonical
+// JSON form, usable as an idempotency key by the apply/diff subsystems:
+// two patterns that differ only in map key order or DependsOn order hash
+// identically. Slices inside Settings/Traits keep their order.
+func (p *Pattern) Hash() string {
+	// Canonical JSON of a map[string]interface{}/string/[]string tree
+	// cannot fail to marshal, so the error is not actionable here.
+	b, _ := json.Marshal(p.canonicalCopy())
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
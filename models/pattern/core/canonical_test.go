@@ -0,0 +1,109 @@
+package core
+
+import (
+	"testing"
+)
+
+// buildVariant constructs two Patterns that are equivalent in content but
+// differ in DependsOn slice order and Settings/Traits map insertion order
+// (Go map literals are re-hashed on every build, but we also reconstruct
+// them from separately-ordered key lists to avoid relying on that).
+func buildVariant(depOrder []string, settingsKeys []string) *Pattern {
+	settings := map[string]interface{}{}
+	for _, k := range settingsKeys {
+		switch k {
+		case "cpu":
+			settings["cpu"] = "100m"
+		case "memory":
+			settings["memory"] = "128Mi"
+		case "nested":
+			settings["nested"] = map[string]interface{}{"b": 2.0, "a": 1.0}
+		}
+	}
+
+	return &Pattern{
+		Name: "test",
+		Services: map[string]*Service{
+			"web": {
+				Type:      "WebService",
+				DependsOn: append([]string{}, depOrder...),
+				Settings:  settings,
+				Traits:    map[string]interface{}{},
+			},
+		},
+	}
+}
+
+func TestHashStableAcrossOrdering(t *testing.T) {
+	a := buildVariant([]string{"db", "cache"}, []string{"cpu", "memory", "nested"})
+	b := buildVariant([]string{"cache", "db"}, []string{"nested", "memory", "cpu"})
+
+	if a.Hash() != b.Hash() {
+		t.Fatalf("expected equivalent patterns to hash identically regardless of ordering, got %s vs %s", a.Hash(), b.Hash())
+	}
+}
+
+func TestHashDiffersOnRealChange(t *testing.T) {
+	a := buildVariant([]string{"db", "cache"}, []string{"cpu", "memory"})
+	b := buildVariant([]string{"db", "cache"}, []string{"cpu"})
+
+	if a.Hash() == b.Hash() {
+		t.Fatalf("expected patterns with genuinely different content to hash differently")
+	}
+}
+
+func TestToCanonicalJSONStableAcrossOrdering(t *testing.T) {
+	a := buildVariant([]string{"db", "cache"}, []string{"cpu", "memory", "nested"})
+	b := buildVariant([]string{"cache", "db"}, []string{"nested", "memory", "cpu"})
+
+	ja, err := a.ToCanonicalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	jb, err := b.ToCanonicalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(ja) != string(jb) {
+		t.Fatalf("expected byte-identical canonical JSON, got:\n%s\nvs\n%s", ja, jb)
+	}
+}
+
+func TestToCanonicalYAMLStableAcrossOrdering(t *testing.T) {
+	a := buildVariant([]string{"db", "cache"}, []string{"cpu", "memory", "nested"})
+	b := buildVariant([]string{"cache", "db"}, []string{"nested", "memory", "cpu"})
+
+	ya, err := a.ToCanonicalYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	yb, err := b.ToCanonicalYAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(ya) != string(yb) {
+		t.Fatalf("expected byte-identical canonical YAML, got:\n%s\nvs\n%s", ya, yb)
+	}
+}
+
+func TestCanonicalCopyDoesNotShareMutableState(t *testing.T) {
+	p := &Pattern{Services: map[string]*Service{
+		"web": {
+			Type:     "WebService",
+			Settings: map[string]interface{}{"nested": map[string]interface{}{"a": 1.0}},
+			Traits:   map[string]interface{}{},
+		},
+	}}
+
+	c := p.canonicalCopy()
+
+	nested := c.Services["web"].Settings["nested"].(map[string]interface{})
+	nested["a"] = 2.0
+
+	original := p.Services["web"].Settings["nested"].(map[string]interface{})
+	if original["a"] != 1.0 {
+		t.Fatalf("expected mutating the canonical copy to leave the original untouched, got %v", original["a"])
+	}
+}
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// ValueChange captures the before/after values of a single changed field.
+type ValueChange struct {
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// ServiceDiff captures the field-level changes detected for a single
+// service that exists in both patterns being compared.
+type ServiceDiff struct {
+	Name string `json:"name"`
+
+	ID        *ValueChange `json:"id,omitempty"`
+	Type      *ValueChange `json:"type,omitempty"`
+	Namespace *ValueChange `json:"namespace,omitempty"`
+
+	DependsOnAdded   []string `json:"dependsOnAdded,omitempty"`
+	DependsOnRemoved []string `json:"dependsOnRemoved,omitempty"`
+
+	// SettingsDiff and TraitsDiff are keyed by a dotted path into the
+	// (possibly nested) Settings/Traits maps, e.g. "resources.limits.cpu"
+	SettingsDiff map[string]ValueChange `json:"settingsDiff,omitempty"`
+	TraitsDiff   map[string]ValueChange `json:"traitsDiff,omitempty"`
+}
+
+// IsEmpty reports whether this service has no detected changes.
+func (sd ServiceDiff) IsEmpty() bool {
+	return sd.ID == nil && sd.Type == nil && sd.Namespace == nil &&
+		len(sd.DependsOnAdded) == 0 && len(sd.DependsOnRemoved) == 0 &&
+		len(sd.SettingsDiff) == 0 && len(sd.TraitsDiff) == 0
+}
+
+// PatternDiff is a structured diff between two Pattern files.
+type PatternDiff struct {
+	ServicesAdded   []string      `json:"servicesAdded,omitempty"`
+	ServicesRemoved []string      `json:"servicesRemoved,omitempty"`
+	ServicesChanged []ServiceDiff `json:"servicesChanged,omitempty"`
+}
+
+// IsEmpty reports whether the two patterns compared were equivalent
+// (modulo any ignored/volatile fields).
+func (pd PatternDiff) IsEmpty() bool {
+	return len(pd.ServicesAdded) == 0 && len(pd.ServicesRemoved) == 0 && len(pd.ServicesChanged) == 0
+}
+
+// ToJSON renders the diff as machine-readable JSON.
+func (pd PatternDiff) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(pd, "", "  ")
+}
+
+// String renders a colored, unified-diff-style representation of the
+// PatternDiff, suitable for printing directly to a terminal, along the
+// lines of `kubecfg diff`.
+func (pd PatternDiff) String() string {
+	var b strings.Builder
+
+	for _, name := range pd.ServicesAdded {
+		fmt.Fprintf(&b, "%s\n", green("+ service "+name))
+	}
+	for _, name := range pd.ServicesRemoved {
+		fmt.Fprintf(&b, "%s\n", red("- service "+name))
+	}
+
+	for _, sd := range pd.ServicesChanged {
+		fmt.Fprintf(&b, "~ service %s\n", sd.Name)
+
+		if sd.ID != nil {
+			writeValueChange(&b, "  id", *sd.ID)
+		}
+		if sd.Type != nil {
+			writeValueChange(&b, "  type", *sd.Type)
+		}
+		if sd.Namespace != nil {
+			writeValueChange(&b, "  namespace", *sd.Namespace)
+		}
+		for _, dep := range sd.DependsOnAdded {
+			fmt.Fprintf(&b, "  %s\n", green("+ dependsOn "+dep))
+		}
+		for _, dep := range sd.DependsOnRemoved {
+			fmt.Fprintf(&b, "  %s\n", red("- dependsOn "+dep))
+		}
+		for _, path := range sortedKeys(sd.SettingsDiff) {
+			writeValueChange(&b, "  settings."+path, sd.SettingsDiff[path])
+		}
+		for _, path := range sortedKeys(sd.TraitsDiff) {
+			writeValueChange(&b, "  traits."+path, sd.TraitsDiff[path])
+		}
+	}
+
+	return b.String()
+}
+
+func writeValueChange(b *strings.Builder, label string, vc ValueChange) {
+	fmt.Fprintf(b, "  %s\n", red(fmt.Sprintf("- %s: %v", strings.TrimSpace(label), vc.Old)))
+	fmt.Fprintf(b, "  %s\n", green(fmt.Sprintf("+ %s: %v", strings.TrimSpace(label), vc.New)))
+}
+
+func green(s string) string { return "\033[32m" + s + "\033[0m" }
+func red(s string) string   { return "\033[31m" + s + "\033[0m" }
+
+// DiffOptions controls which fields Pattern.Diff ignores when comparing
+// two patterns, so that cosmetic or auto-generated noise does not show
+// up as an intent-level change.
+type DiffOptions struct {
+	// IgnoreID ignores the auto-generated Service.ID field.
+	IgnoreID bool
+	// IgnoreMeshmapPosition ignores the "meshmap" position trait, which
+	// is regenerated on every render and carries no user intent.
+	IgnoreMeshmapPosition bool
+}
+
+// DiffOption configures DiffOptions.
+type DiffOption func(*DiffOptions)
+
+// IgnoreVolatileFields ignores the fields known to be auto-generated or
+// cosmetic: Service.ID and the "meshmap" position trait, so that users can
+// compare intent without cosmetic noise.
+func IgnoreVolatileFields() DiffOption {
+	return func(o *DiffOptions) {
+		o.IgnoreID = true
+		o.IgnoreMeshmapPosition = true
+	}
+}
+
+// Diff produces a structured diff between p and other: services added,
+// services removed, and per-service field-level changes (Type,
+// Namespace, DependsOn set differences, and a recursive diff over
+// Settings and Traits).
+func (p *Pattern) Diff(other *Pattern, opts ...DiffOption) (PatternDiff, error) {
+	if other == nil {
+		return PatternDiff{}, fmt.Errorf("cannot diff against a nil pattern")
+	}
+
+	options := DiffOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var diff PatternDiff
+
+	for name := range other.Services {
+		if _, ok := p.Services[name]; !ok {
+			diff.ServicesAdded = append(diff.ServicesAdded, name)
+		}
+	}
+	sort.Strings(diff.ServicesAdded)
+
+	for name := range p.Services {
+		if _, ok := other.Services[name]; !ok {
+			diff.ServicesRemoved = append(diff.ServicesRemoved, name)
+		}
+	}
+	sort.Strings(diff.ServicesRemoved)
+
+	var names []string
+	for name := range p.Services {
+		if _, ok := other.Services[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sd := diffService(name, p.Services[name], other.Services[name], options)
+		if !sd.IsEmpty() {
+			diff.ServicesChanged = append(diff.ServicesChanged, sd)
+		}
+	}
+
+	return diff, nil
+}
+
+func diffService(name string, a, b *Service, options DiffOptions) ServiceDiff {
+	sd := ServiceDiff{Name: name}
+
+	if !options.IgnoreID {
+		if aID, bID := idString(a.ID), idString(b.ID); aID != bID {
+			sd.ID = &ValueChange{Old: aID, New: bID}
+		}
+	}
+
+	if a.Type != b.Type {
+		sd.Type = &ValueChange{Old: a.Type, New: b.Type}
+	}
+	if a.Namespace != b.Namespace {
+		sd.Namespace = &ValueChange{Old: a.Namespace, New: b.Namespace}
+	}
+
+	sd.DependsOnAdded, sd.DependsOnRemoved = diffStringSets(a.DependsOn, b.DependsOn)
+
+	sd.SettingsDiff = diffMaps("", a.Settings, b.Settings, nil)
+
+	ignoredTraitKeys := map[string]bool{}
+	if options.IgnoreMeshmapPosition {
+		ignoredTraitKeys["meshmap"] = true
+	}
+	sd.TraitsDiff = diffMaps("", a.Traits, b.Traits, ignoredTraitKeys)
+
+	return sd
+}
+
+// idString renders a Service.ID for comparison/display, treating a nil ID
+// the same as an unset one.
+func idString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func diffStringSets(a, b []string) (added, removed []string) {
+	setA := map[string]bool{}
+	for _, s := range a {
+		setA[s] = true
+	}
+	setB := map[string]bool{}
+	for _, s := range b {
+		setB[s] = true
+	}
+
+	for s := range setB {
+		if !setA[s] {
+			added = append(added, s)
+		}
+	}
+	for s := range setA {
+		if !setB[s] {
+			removed = append(removed, s)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return
+}
+
+// diffMaps recursively walks two map[string]interface{} trees and
+// records every leaf whose value differs, keyed by its dotted path. A key
+// present on only one side is recorded once at its own path with its whole
+// value; a nested map there is not expanded into leaves.
+// Top-level keys present in ignoredKeys are skipped entirely.
+func diffMaps(prefix string, a, b map[string]interface{}, ignoredKeys map[string]bool) map[string]ValueChange {
+	out := map[string]ValueChange{}
+
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	for k := range keys {
+		if prefix == "" && ignoredKeys[k] {
+			continue
+		}
+
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+
+		av, aok := a[k]
+		bv, bok := b[k]
+
+		if !aok {
+			out[path] = ValueChange{New: bv}
+			continue
+		}
+		if !bok {
+			out[path] = ValueChange{Old: av}
+			continue
+		}
+
+		amap, aIsMap := av.(map[string]interface{})
+		bmap, bIsMap := bv.(map[string]interface{})
+		if aIsMap && bIsMap {
+			for subPath, vc := range diffMaps(path, amap, bmap, nil) {
+				out[subPath] = vc
+			}
+			continue
+		}
+
+		if !valuesEqual(av, bv) {
+			out[path] = ValueChange{Old: av, New: bv}
+		}
+	}
+
+	return out
+}
+
+func valuesEqual(a, b interface{}) bool {
+	aByt, aErr := json.Marshal(a)
+	bByt, bErr := json.Marshal(b)
+	if aErr != nil || bErr != nil {
+		return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+	}
+	return string(aByt) == string(bByt)
+}
+
+func sortedKeys(m map[string]ValueChange) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestDiffAddedAndRemovedServices(t *testing.T) {
+	a := &Pattern{Services: map[string]*Service{
+		"gone": {Type: "WebService", Settings: map[string]interface{}{}, Traits: map[string]interface{}{}},
+	}}
+	b := &Pattern{Services: map[string]*Service{
+		"new": {Type: "WebService", Settings: map[string]interface{}{}, Traits: map[string]interface{}{}},
+	}}
+
+	diff, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(diff.ServicesAdded) != 1 || diff.ServicesAdded[0] != "new" {
+		t.Fatalf("expected ServicesAdded [new], got %v", diff.ServicesAdded)
+	}
+	if len(diff.ServicesRemoved) != 1 || diff.ServicesRemoved[0] != "gone" {
+		t.Fatalf("expected ServicesRemoved [gone], got %v", diff.ServicesRemoved)
+	}
+	if len(diff.ServicesChanged) != 0 {
+		t.Fatalf("expected no ServicesChanged, got %v", diff.ServicesChanged)
+	}
+}
+
+func TestDiffNestedSettingsAndTraits(t *testing.T) {
+	a := &Pattern{Services: map[string]*Service{
+		"web": {
+			Type: "WebService",
+			Settings: map[string]interface{}{
+				"resources": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": "100m"},
+				},
+			},
+			Traits: map[string]interface{}{},
+		},
+	}}
+	b := &Pattern{Services: map[string]*Service{
+		"web": {
+			Type: "WebService",
+			Settings: map[string]interface{}{
+				"resources": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": "200m"},
+				},
+			},
+			Traits: map[string]interface{}{},
+		},
+	}}
+
+	diff, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(diff.ServicesChanged) != 1 {
+		t.Fatalf("expected 1 changed service, got %d", len(diff.ServicesChanged))
+	}
+
+	sd := diff.ServicesChanged[0]
+	vc, ok := sd.SettingsDiff["resources.limits.cpu"]
+	if !ok {
+		t.Fatalf("expected a settings diff at resources.limits.cpu, got %v", sd.SettingsDiff)
+	}
+	if vc.Old != "100m" || vc.New != "200m" {
+		t.Fatalf("expected 100m -> 200m, got %v -> %v", vc.Old, vc.New)
+	}
+}
+
+func TestDiffDependsOnSet(t *testing.T) {
+	a := &Pattern{Services: map[string]*Service{
+		"web": {Type: "WebService", DependsOn: []string{"db"}, Settings: map[string]interface{}{}, Traits: map[string]interface{}{}},
+	}}
+	b := &Pattern{Services: map[string]*Service{
+		"web": {Type: "WebService", DependsOn: []string{"cache"}, Settings: map[string]interface{}{}, Traits: map[string]interface{}{}},
+	}}
+
+	diff, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(diff.ServicesChanged) != 1 {
+		t.Fatalf("expected 1 changed service, got %d", len(diff.ServicesChanged))
+	}
+
+	sd := diff.ServicesChanged[0]
+	sort.Strings(sd.DependsOnAdded)
+	sort.Strings(sd.DependsOnRemoved)
+
+	if len(sd.DependsOnAdded) != 1 || sd.DependsOnAdded[0] != "cache" {
+		t.Fatalf("expected DependsOnAdded [cache], got %v", sd.DependsOnAdded)
+	}
+	if len(sd.DependsOnRemoved) != 1 || sd.DependsOnRemoved[0] != "db" {
+		t.Fatalf("expected DependsOnRemoved [db], got %v", sd.DependsOnRemoved)
+	}
+}
+
+func TestDiffIgnoreVolatileFields(t *testing.T) {
+	idA, err := uuid.NewV4()
+	if err != nil {
+		t.Fatalf("failed to generate uuid: %s", err)
+	}
+	idB, err := uuid.NewV4()
+	if err != nil {
+		t.Fatalf("failed to generate uuid: %s", err)
+	}
+
+	a := &Pattern{Services: map[string]*Service{
+		"web": {
+			ID:   &idA,
+			Type: "WebService",
+			Traits: map[string]interface{}{
+				"meshmap": map[string]interface{}{"position": map[string]interface{}{"posX": 1.0, "posY": 2.0}},
+			},
+			Settings: map[string]interface{}{},
+		},
+	}}
+	b := &Pattern{Services: map[string]*Service{
+		"web": {
+			ID:   &idB,
+			Type: "WebService",
+			Traits: map[string]interface{}{
+				"meshmap": map[string]interface{}{"position": map[string]interface{}{"posX": 99.0, "posY": 42.0}},
+			},
+			Settings: map[string]interface{}{},
+		},
+	}}
+
+	// Without the option, the differing ID and meshmap position show up.
+	diff, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(diff.ServicesChanged) != 1 {
+		t.Fatalf("expected 1 changed service without ignoring volatile fields, got %d", len(diff.ServicesChanged))
+	}
+	sd := diff.ServicesChanged[0]
+	if sd.ID == nil {
+		t.Fatalf("expected ID diff to be reported without IgnoreVolatileFields")
+	}
+	if len(sd.TraitsDiff) == 0 {
+		t.Fatalf("expected meshmap trait diff to be reported without IgnoreVolatileFields")
+	}
+
+	// With the option, both are ignored and the services are equivalent.
+	diff, err = a.Diff(b, IgnoreVolatileFields())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !diff.IsEmpty() {
+		t.Fatalf("expected no changes with IgnoreVolatileFields, got %+v", diff)
+	}
+}
+
+func TestDiffNilOther(t *testing.T) {
+	a := &Pattern{Services: map[string]*Service{}}
+	if _, err := a.Diff(nil); err == nil {
+		t.Fatalf("expected an error diffing against a nil pattern")
+	}
+}
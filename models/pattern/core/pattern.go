@@ -1,10 +1,11 @@
 package core
 
 import (
-	"crypto/rand"
+	"context"
 	"encoding/json"
 	"fmt"
-	"math/big"
+	"hash/fnv"
+	"sort"
 
 	"github.com/gofrs/uuid"
 	"github.com/layer5io/meshery/models/pattern/utils"
@@ -45,9 +46,14 @@ type Service struct {
 	Traits   map[string]interface{} `yaml:"traits,omitempty" json:"traits,omitempty"`
 }
 
-// NewPatternFile takes in raw yaml and encodes it into a construct
-func NewPatternFile(yml []byte) (af Pattern, err error) {
+// NewPatternFile takes in raw yaml and encodes it into a construct. If one
+// or more validators are given, the resulting Pattern is additionally run
+// through Validate, and any SeverityError diagnostic fails the call.
+func NewPatternFile(yml []byte, validators ...Validator) (af Pattern, err error) {
 	err = yaml.Unmarshal(yml, &af)
+	if err != nil {
+		return
+	}
 
 	for svcName, svc := range af.Services {
 		// If an explicit name is not given to the service then use
@@ -67,6 +73,18 @@ func NewPatternFile(yml []byte) (af Pattern, err error) {
 		}
 	}
 
+	if len(validators) > 0 {
+		var blocking validationErrors
+		for _, ve := range af.Validate(context.Background(), validators...) {
+			if ve.Severity == SeverityError {
+				blocking = append(blocking, ve)
+			}
+		}
+		if len(blocking) > 0 {
+			err = blocking
+		}
+	}
+
 	return
 }
 
@@ -98,31 +116,50 @@ func (p *Pattern) GenerateApplicationConfiguration() (v1alpha1.Configuration, er
 		ObjectMeta: v1.ObjectMeta{Name: p.Name},
 	}
 
-	// Create configs for each component
-	for k, v := range p.Services {
-		// Indicates that map for properties is not empty
-		if len(v.Traits) > 0 {
-			specComp := v1alpha1.ConfigurationSpecComponent{
-				ComponentName: k,
-			}
+	// Resolve DependsOn into a dependency-respecting deployment order so
+	// that Spec.Components is emitted deterministically, rather than
+	// relying on Go's non-deterministic map iteration.
+	waves, err := p.DeploymentPlan()
+	if err != nil {
+		return config, err
+	}
 
-			for k2, v2 := range v.Traits {
-				castToMap, ok := v2.(map[string]interface{})
+	// Create configs for each component, in wave order
+	for _, wave := range waves {
+		for _, k := range wave {
+			v := p.Services[k]
 
-				trait := v1alpha1.ConfigurationSpecComponentTrait{
-					Name: k2,
+			// Indicates that map for properties is not empty
+			if len(v.Traits) > 0 {
+				specComp := v1alpha1.ConfigurationSpecComponent{
+					ComponentName: k,
 				}
 
-				if !ok {
-					castToMap = map[string]interface{}{}
+				var traitNames []string
+				for k2 := range v.Traits {
+					traitNames = append(traitNames, k2)
 				}
+				sort.Strings(traitNames)
 
-				trait.Properties = castToMap
+				for _, k2 := range traitNames {
+					v2 := v.Traits[k2]
+					castToMap, ok := v2.(map[string]interface{})
 
-				specComp.Traits = append(specComp.Traits, trait)
-			}
+					trait := v1alpha1.ConfigurationSpecComponentTrait{
+						Name: k2,
+					}
 
-			config.Spec.Components = append(config.Spec.Components, specComp)
+					if !ok {
+						castToMap = map[string]interface{}{}
+					}
+
+					trait.Properties = castToMap
+
+					specComp.Traits = append(specComp.Traits, trait)
+				}
+
+				config.Spec.Components = append(config.Spec.Components, specComp)
+			}
 		}
 	}
 
@@ -134,43 +171,6 @@ func (p *Pattern) GetServiceType(name string) string {
 	return p.Services[name].Type
 }
 
-// ToCytoscapeJS converts pattern file into cytoscape object
-func (p *Pattern) ToCytoscapeJS() (cytoscapejs.GraphElem, error) {
-	var cy cytoscapejs.GraphElem
-
-	// Not specifying any cytoscapejs layout
-	// should fallback to "default" layout
-
-	// Not specifying styles, may get applied on the
-	// client side
-
-	// Set up the nodes
-	for name, svc := range p.Services {
-		elemData := cytoscapejs.ElemData{
-			ID: name, // Assuming that the service names are unique
-		}
-
-		elemPosition, err := getCytoscapeJSPosition(svc)
-		if err != nil {
-			return cy, err
-		}
-
-		elem := cytoscapejs.Element{
-			Data:       elemData,
-			Position:   &elemPosition,
-			Selectable: true,
-			Grabbable:  true,
-			Scratch: map[string]Service{
-				"_data": *svc,
-			},
-		}
-
-		cy.Elements = append(cy.Elements, elem)
-	}
-
-	return cy, nil
-}
-
 // ToYAML converts a patternfile to yaml
 func (p *Pattern) ToYAML() ([]byte, error) {
 	return yaml.Marshal(p)
@@ -239,18 +239,18 @@ func getCytoscapeJSPosition(svc *Service) (cytoscapejs.Position, error) {
 	mpi, ok := svc.Traits["meshmap"]
 
 	if !ok {
-		randX, err := rand.Int(rand.Reader, big.NewInt(100))
-		if err != nil {
-			return pos, err
-		}
-		randY, err := rand.Int(rand.Reader, big.NewInt(100))
-		if err != nil {
-			return pos, err
-		}
-
-		pos := cytoscapejs.Position{}
-		pos.X, _ = big.NewFloat(0).SetInt(randX).Float64()
-		pos.Y, _ = big.NewFloat(0).SetInt(randY).Float64()
+		// Derive a stable pseudo-position from the service name rather than
+		// drawing a fresh random one on every call: a service that never
+		// opted into an explicit "meshmap" position should render at the
+		// same spot every time, so that repeated serializations/hashes of
+		// the same pattern don't drift, and so this read-only conversion
+		// never needs to mutate the Service it was given.
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(svc.Name))
+		sum := h.Sum32()
+
+		pos.X = float64(sum % 100)
+		pos.Y = float64((sum / 100) % 100)
 
 		return pos, nil
 	}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cytoscapejs "gonum.org/v1/gonum/graph/formats/cytoscapejs"
+)
+
+// CycleError is returned by DeploymentPlan when the DependsOn graph
+// contains a cycle, naming the services involved in the offending cycle.
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle detected: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// DeploymentPlan builds a directed graph over services from their
+// DependsOn references, validates that every referenced dependency
+// exists, detects cycles, and returns a topologically ordered list of
+// "waves" - each inner slice holds the services that can be deployed in
+// parallel because they share the same depth in the dependency DAG.
+func (p *Pattern) DeploymentPlan() ([][]string, error) {
+	// Validate references and build in-degree/adjacency.
+	inDegree := make(map[string]int, len(p.Services))
+	dependents := make(map[string][]string, len(p.Services))
+
+	for name := range p.Services {
+		inDegree[name] = 0
+	}
+
+	for name, svc := range p.Services {
+		for _, dep := range svc.DependsOn {
+			if _, ok := p.Services[dep]; !ok {
+				return nil, fmt.Errorf("service %q depends on %q, which does not exist", name, dep)
+			}
+			inDegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var waves [][]string
+	remaining := len(p.Services)
+
+	for remaining > 0 {
+		var wave []string
+		for name, deg := range inDegree {
+			if deg == 0 {
+				wave = append(wave, name)
+			}
+		}
+
+		if len(wave) == 0 {
+			return nil, &CycleError{Cycle: findCycle(p.Services)}
+		}
+
+		sort.Strings(wave)
+		waves = append(waves, wave)
+
+		for _, name := range wave {
+			delete(inDegree, name)
+			remaining--
+			for _, dependent := range dependents[name] {
+				inDegree[dependent]--
+			}
+		}
+	}
+
+	return waves, nil
+}
+
+// findCycle locates one cycle in the DependsOn graph for error reporting,
+// via DFS with a recursion-stack coloring.
+func findCycle(services map[string]*Service) []string {
+	const (
+		white = 0
+		gray  = 1
+		black = 2
+	)
+
+	color := make(map[string]int, len(services))
+	var stack []string
+
+	var names []string
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		color[name] = gray
+		stack = append(stack, name)
+
+		deps := append([]string{}, services[name].DependsOn...)
+		sort.Strings(deps)
+
+		for _, dep := range deps {
+			switch color[dep] {
+			case white:
+				if cyc := visit(dep); cyc != nil {
+					return cyc
+				}
+			case gray:
+				// Found the back-edge that closes the cycle.
+				start := 0
+				for i, s := range stack {
+					if s == dep {
+						start = i
+						break
+					}
+				}
+				cyc := append([]string{}, stack[start:]...)
+				return append(cyc, dep)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		color[name] = black
+		return nil
+	}
+
+	for _, name := range names {
+		if color[name] == white {
+			if cyc := visit(name); cyc != nil {
+				return cyc
+			}
+		}
+	}
+
+	return nil
+}
+
+// CytoscapeOption configures ToCytoscapeJS.
+type CytoscapeOption func(*cytoscapeOptions)
+
+type cytoscapeOptions struct {
+	withDependsOnEdges bool
+}
+
+// WithDependsOnEdges makes ToCytoscapeJS emit an edge for every DependsOn
+// reference, so the resulting graph visualizes dependencies rather than
+// only nodes.
+func WithDependsOnEdges() CytoscapeOption {
+	return func(o *cytoscapeOptions) {
+		o.withDependsOnEdges = true
+	}
+}
+
+// ToCytoscapeJS converts pattern file into cytoscape object
+func (p *Pattern) ToCytoscapeJS(opts ...CytoscapeOption) (cytoscapejs.GraphElem, error) {
+	var cy cytoscapejs.GraphElem
+
+	options := cytoscapeOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// Not specifying any cytoscapejs layout
+	// should fallback to "default" layout
+
+	// Not specifying styles, may get applied on the
+	// client side
+
+	// Set up the nodes
+	for name, svc := range p.Services {
+		elemData := cytoscapejs.ElemData{
+			ID: name, // Assuming that the service names are unique
+		}
+
+		elemPosition, err := getCytoscapeJSPosition(svc)
+		if err != nil {
+			return cy, err
+		}
+
+		elem := cytoscapejs.Element{
+			Data:       elemData,
+			Position:   &elemPosition,
+			Selectable: true,
+			Grabbable:  true,
+			Scratch: map[string]Service{
+				"_data": *svc,
+			},
+		}
+
+		cy.Elements = append(cy.Elements, elem)
+	}
+
+	// Set up edges from DependsOn, so the graph view visualizes
+	// dependencies rather than only nodes.
+	if options.withDependsOnEdges {
+		for name, svc := range p.Services {
+			for _, dep := range svc.DependsOn {
+				edge := cytoscapejs.Element{
+					Data: cytoscapejs.ElemData{
+						ID:     fmt.Sprintf("%s-depends-on-%s", name, dep),
+						Source: dep,
+						Target: name,
+					},
+					Selectable: true,
+				}
+
+				cy.Elements = append(cy.Elements, edge)
+			}
+		}
+	}
+
+	return cy, nil
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestPattern(services map[string][]string) *Pattern {
+	p := &Pattern{Services: map[string]*Service{}}
+	for name, deps := range services {
+		p.Services[name] = &Service{
+			Type:      "WebService",
+			DependsOn: deps,
+			Settings:  map[string]interface{}{},
+			Traits:    map[string]interface{}{},
+		}
+	}
+	return p
+}
+
+func TestDeploymentPlanDiamondDAG(t *testing.T) {
+	p := newTestPattern(map[string][]string{
+		"a": nil,
+		"b": {"a"},
+		"c": {"a"},
+		"d": {"b", "c"},
+	})
+
+	waves, err := p.DeploymentPlan()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := [][]string{{"a"}, {"b", "c"}, {"d"}}
+	if !reflect.DeepEqual(waves, want) {
+		t.Fatalf("expected waves %v, got %v", want, waves)
+	}
+}
+
+func TestDeploymentPlanDirectCycle(t *testing.T) {
+	p := newTestPattern(map[string][]string{
+		"a": {"b"},
+		"b": {"a"},
+	})
+
+	_, err := p.DeploymentPlan()
+	if err == nil {
+		t.Fatalf("expected a cycle error")
+	}
+
+	cycErr, ok := err.(*CycleError)
+	if !ok {
+		t.Fatalf("expected a *CycleError, got %T: %s", err, err)
+	}
+	if len(cycErr.Cycle) < 2 {
+		t.Fatalf("expected a cycle naming at least 2 services, got %v", cycErr.Cycle)
+	}
+}
+
+func TestDeploymentPlanCycleNotAtDFSRoot(t *testing.T) {
+	// "a" is visited first (alphabetically), is not itself part of the
+	// cycle, and leads into a cycle among b -> c -> d -> b, whose back-edge
+	// points at "b", not at the DFS root "a". This exercises the branch
+	// that searches the recursion stack for the back-edge's target rather
+	// than assuming it is always index 0.
+	p := newTestPattern(map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"d"},
+		"d": {"b"},
+	})
+
+	_, err := p.DeploymentPlan()
+	if err == nil {
+		t.Fatalf("expected a cycle error")
+	}
+
+	cycErr, ok := err.(*CycleError)
+	if !ok {
+		t.Fatalf("expected a *CycleError, got %T: %s", err, err)
+	}
+
+	// The cycle must start and end on the same service (the back-edge
+	// target), and must not include "a", which sits outside the cycle.
+	if len(cycErr.Cycle) == 0 || cycErr.Cycle[0] != cycErr.Cycle[len(cycErr.Cycle)-1] {
+		t.Fatalf("expected cycle to start/end on the same service, got %v", cycErr.Cycle)
+	}
+	for _, s := range cycErr.Cycle {
+		if s == "a" {
+			t.Fatalf("expected cycle to exclude %q, which is outside the cycle, got %v", "a", cycErr.Cycle)
+		}
+	}
+}
+
+func TestDeploymentPlanSelfReference(t *testing.T) {
+	p := newTestPattern(map[string][]string{
+		"a": {"a"},
+	})
+
+	_, err := p.DeploymentPlan()
+	if err == nil {
+		t.Fatalf("expected a cycle error for a service depending on itself")
+	}
+	if _, ok := err.(*CycleError); !ok {
+		t.Fatalf("expected a *CycleError, got %T: %s", err, err)
+	}
+}
+
+func TestDeploymentPlanMissingDependency(t *testing.T) {
+	p := newTestPattern(map[string][]string{
+		"a": {"ghost"},
+	})
+
+	_, err := p.DeploymentPlan()
+	if err == nil {
+		t.Fatalf("expected an error for a missing dependency")
+	}
+	if _, ok := err.(*CycleError); ok {
+		t.Fatalf("expected a plain validation error, not a *CycleError, got %v", err)
+	}
+}
@@ -0,0 +1,316 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Severity indicates how serious a ValidationError is.
+type Severity string
+
+const (
+	// SeverityError indicates the pattern cannot be safely converted to
+	// OAM without fixing the offending field.
+	SeverityError Severity = "error"
+	// SeverityWarning indicates a likely mistake that does not by itself
+	// block conversion.
+	SeverityWarning Severity = "warning"
+)
+
+// ValidationError carries enough detail - which service, which field, and
+// how severe - for a UI to highlight the exact offending field, turning
+// silent failures downstream in OAM conversion into upfront, structured
+// diagnostics.
+type ValidationError struct {
+	Service  string   `json:"service"`
+	Path     string   `json:"path"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (v ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", v.Service, v.Path, v.Message)
+}
+
+// Validator inspects a Pattern and reports any diagnostics it finds.
+type Validator interface {
+	Validate(ctx context.Context, p *Pattern) []ValidationError
+}
+
+// Validate runs every given Validator against p and returns the
+// concatenation of their diagnostics.
+func (p *Pattern) Validate(ctx context.Context, validators ...Validator) []ValidationError {
+	var errs []ValidationError
+	for _, v := range validators {
+		errs = append(errs, v.Validate(ctx, p)...)
+	}
+	return errs
+}
+
+// dns1123LabelRegexp matches a valid DNS-1123 label, e.g. a Kubernetes
+// Namespace name.
+var dns1123LabelRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// RequiredFieldsValidator checks that Type is non-empty and Namespace,
+// when given, is a valid DNS-1123 label.
+type RequiredFieldsValidator struct{}
+
+// Validate implements Validator.
+func (RequiredFieldsValidator) Validate(_ context.Context, p *Pattern) []ValidationError {
+	var errs []ValidationError
+
+	for _, name := range sortedServiceNames(p) {
+		svc := p.Services[name]
+
+		if svc.Type == "" {
+			errs = append(errs, ValidationError{
+				Service: name, Path: "type", Severity: SeverityError,
+				Message: "type must not be empty",
+			})
+		}
+
+		if svc.Namespace != "" && (len(svc.Namespace) > 63 || !dns1123LabelRegexp.MatchString(svc.Namespace)) {
+			errs = append(errs, ValidationError{
+				Service: name, Path: "namespace", Severity: SeverityError,
+				Message: fmt.Sprintf("namespace %q is not a valid DNS-1123 label", svc.Namespace),
+			})
+		}
+	}
+
+	return errs
+}
+
+// ReferenceIntegrityValidator checks that every entry in DependsOn names
+// an existing service and that no service depends on itself.
+type ReferenceIntegrityValidator struct{}
+
+// Validate implements Validator.
+func (ReferenceIntegrityValidator) Validate(_ context.Context, p *Pattern) []ValidationError {
+	var errs []ValidationError
+
+	for _, name := range sortedServiceNames(p) {
+		svc := p.Services[name]
+
+		for _, dep := range svc.DependsOn {
+			if dep == name {
+				errs = append(errs, ValidationError{
+					Service: name, Path: "dependsOn", Severity: SeverityError,
+					Message: fmt.Sprintf("service %q cannot depend on itself", name),
+				})
+				continue
+			}
+
+			if _, ok := p.Services[dep]; !ok {
+				errs = append(errs, ValidationError{
+					Service: name, Path: "dependsOn", Severity: SeverityError,
+					Message: fmt.Sprintf("depends on %q, which does not exist", dep),
+				})
+			}
+		}
+	}
+
+	return errs
+}
+
+// PropertySchema is a minimal, JSON-Schema-inspired description of a
+// single trait property: the JSON type it must hold, and, for nested
+// objects, the schema of its own properties.
+type PropertySchema struct {
+	Type       string                    `json:"type"`
+	Required   []string                  `json:"required,omitempty"`
+	Properties map[string]PropertySchema `json:"properties,omitempty"`
+}
+
+// TraitSchema is the JSON Schema a registered trait type declares for its
+// properties.
+type TraitSchema struct {
+	Required   []string                  `json:"required,omitempty"`
+	Properties map[string]PropertySchema `json:"properties,omitempty"`
+}
+
+// TraitSchemaValidator validates svc.Traits[name] against the JSON Schema
+// registered for that trait name.
+type TraitSchemaValidator struct {
+	Schemas map[string]TraitSchema
+}
+
+// Validate implements Validator.
+func (v TraitSchemaValidator) Validate(_ context.Context, p *Pattern) []ValidationError {
+	var errs []ValidationError
+
+	for _, name := range sortedServiceNames(p) {
+		svc := p.Services[name]
+
+		var traitNames []string
+		for traitName := range svc.Traits {
+			traitNames = append(traitNames, traitName)
+		}
+		sort.Strings(traitNames)
+
+		for _, traitName := range traitNames {
+			schema, ok := v.Schemas[traitName]
+			if !ok {
+				continue
+			}
+
+			props, ok := svc.Traits[traitName].(map[string]interface{})
+			if !ok {
+				errs = append(errs, ValidationError{
+					Service: name, Path: "traits." + traitName, Severity: SeverityError,
+					Message: "trait properties must be an object",
+				})
+				continue
+			}
+
+			errs = append(errs, validateAgainstSchema(name, "traits."+traitName, schema.Required, schema.Properties, props)...)
+		}
+	}
+
+	return errs
+}
+
+func validateAgainstSchema(service, path string, required []string, properties map[string]PropertySchema, value map[string]interface{}) []ValidationError {
+	var errs []ValidationError
+
+	for _, req := range required {
+		if _, ok := value[req]; !ok {
+			errs = append(errs, ValidationError{
+				Service: service, Path: path + "." + req, Severity: SeverityError,
+				Message: "required property is missing",
+			})
+		}
+	}
+
+	var propNames []string
+	for propName := range properties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	for _, propName := range propNames {
+		propSchema := properties[propName]
+		propPath := path + "." + propName
+
+		val, ok := value[propName]
+		if !ok {
+			continue
+		}
+
+		if !jsonTypeMatches(propSchema.Type, val) {
+			errs = append(errs, ValidationError{
+				Service: service, Path: propPath, Severity: SeverityError,
+				Message: fmt.Sprintf("expected type %q, got %T", propSchema.Type, val),
+			})
+			continue
+		}
+
+		if propSchema.Type == "object" && len(propSchema.Properties) > 0 {
+			nested, ok := val.(map[string]interface{})
+			if ok {
+				errs = append(errs, validateAgainstSchema(service, propPath, propSchema.Required, propSchema.Properties, nested)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func jsonTypeMatches(schemaType string, val interface{}) bool {
+	switch schemaType {
+	case "", "any":
+		return true
+	case "string":
+		_, ok := val.(string)
+		return ok
+	case "number":
+		switch val.(type) {
+		case float64, int, int64, uint64:
+			return true
+		}
+		return false
+	case "integer":
+		switch n := val.(type) {
+		case int, int64, uint64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		}
+		return false
+	case "boolean":
+		_, ok := val.(bool)
+		return ok
+	case "object":
+		_, ok := val.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := val.([]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+// ComponentRegistry resolves whether a component kind is registered and
+// known to Meshery.
+type ComponentRegistry interface {
+	HasComponent(kind string) bool
+}
+
+// ComponentCapabilityValidator resolves svc.Type against a registered
+// component registry so unknown component kinds are surfaced before
+// GetApplicationComponent is called.
+type ComponentCapabilityValidator struct {
+	Registry ComponentRegistry
+}
+
+// Validate implements Validator.
+func (v ComponentCapabilityValidator) Validate(_ context.Context, p *Pattern) []ValidationError {
+	var errs []ValidationError
+
+	if v.Registry == nil {
+		return errs
+	}
+
+	for _, name := range sortedServiceNames(p) {
+		svc := p.Services[name]
+
+		if svc.Type == "" {
+			continue
+		}
+
+		if !v.Registry.HasComponent(svc.Type) {
+			errs = append(errs, ValidationError{
+				Service: name, Path: "type", Severity: SeverityError,
+				Message: fmt.Sprintf("unknown component kind %q", svc.Type),
+			})
+		}
+	}
+
+	return errs
+}
+
+func sortedServiceNames(p *Pattern) []string {
+	names := make([]string, 0, len(p.Services))
+	for name := range p.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validationErrors aggregates ValidationError entries of SeverityError
+// into a single error, so NewPatternFile can fail the call with one
+// descriptive error while still exposing the full structured list via
+// Pattern.Validate.
+type validationErrors []ValidationError
+
+func (ve validationErrors) Error() string {
+	msgs := make([]string, 0, len(ve))
+	for _, e := range ve {
+		msgs = append(msgs, e.Error())
+	}
+	return fmt.Sprintf("pattern validation failed: %s", strings.Join(msgs, "; "))
+}
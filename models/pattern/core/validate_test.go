@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRequiredFieldsValidator(t *testing.T) {
+	p := &Pattern{Services: map[string]*Service{
+		"missing-type": {Namespace: "default"},
+		"bad-ns":       {Type: "WebService", Namespace: "Not_Valid"},
+		"too-long-ns":  {Type: "WebService", Namespace: strings.Repeat("a", 64)},
+		"ok":           {Type: "WebService", Namespace: strings.Repeat("a", 63)},
+	}}
+
+	errs := p.Validate(context.Background(), RequiredFieldsValidator{})
+
+	byService := map[string][]ValidationError{}
+	for _, e := range errs {
+		byService[e.Service] = append(byService[e.Service], e)
+	}
+
+	if len(byService["missing-type"]) != 1 || byService["missing-type"][0].Path != "type" {
+		t.Fatalf("expected a single type error for missing-type, got %v", byService["missing-type"])
+	}
+	if len(byService["bad-ns"]) != 1 || byService["bad-ns"][0].Path != "namespace" {
+		t.Fatalf("expected a single namespace error for bad-ns, got %v", byService["bad-ns"])
+	}
+	if len(byService["too-long-ns"]) != 1 || byService["too-long-ns"][0].Path != "namespace" {
+		t.Fatalf("expected a single namespace error for too-long-ns (64 chars), got %v", byService["too-long-ns"])
+	}
+	if len(byService["ok"]) != 0 {
+		t.Fatalf("expected no errors for a valid 63-char namespace, got %v", byService["ok"])
+	}
+}
+
+func TestReferenceIntegrityValidator(t *testing.T) {
+	p := &Pattern{Services: map[string]*Service{
+		"self":    {Type: "WebService", DependsOn: []string{"self"}},
+		"missing": {Type: "WebService", DependsOn: []string{"ghost"}},
+		"ok":      {Type: "WebService"},
+	}}
+
+	errs := p.Validate(context.Background(), ReferenceIntegrityValidator{})
+
+	var selfErr, missingErr bool
+	for _, e := range errs {
+		if e.Service == "self" && strings.Contains(e.Message, "cannot depend on itself") {
+			selfErr = true
+		}
+		if e.Service == "missing" && strings.Contains(e.Message, "does not exist") {
+			missingErr = true
+		}
+	}
+
+	if !selfErr {
+		t.Fatalf("expected a self-reference error, got %v", errs)
+	}
+	if !missingErr {
+		t.Fatalf("expected a missing-dependency error, got %v", errs)
+	}
+}
+
+func TestTraitSchemaValidator(t *testing.T) {
+	schemas := map[string]TraitSchema{
+		"scaling": {
+			Required: []string{"min"},
+			Properties: map[string]PropertySchema{
+				"min": {Type: "integer"},
+				"max": {Type: "integer"},
+			},
+		},
+	}
+
+	p := &Pattern{Services: map[string]*Service{
+		"missing-required": {
+			Type:   "WebService",
+			Traits: map[string]interface{}{"scaling": map[string]interface{}{"max": float64(5)}},
+		},
+		"wrong-type": {
+			Type:   "WebService",
+			Traits: map[string]interface{}{"scaling": map[string]interface{}{"min": "not-a-number"}},
+		},
+		"valid": {
+			Type:   "WebService",
+			Traits: map[string]interface{}{"scaling": map[string]interface{}{"min": float64(1), "max": float64(10)}},
+		},
+	}}
+
+	errs := p.Validate(context.Background(), TraitSchemaValidator{Schemas: schemas})
+
+	byService := map[string][]ValidationError{}
+	for _, e := range errs {
+		byService[e.Service] = append(byService[e.Service], e)
+	}
+
+	if len(byService["missing-required"]) != 1 {
+		t.Fatalf("expected one error for the missing required property, got %v", byService["missing-required"])
+	}
+	if len(byService["wrong-type"]) != 1 {
+		t.Fatalf("expected one error for the wrong-typed property, got %v", byService["wrong-type"])
+	}
+	if len(byService["valid"]) != 0 {
+		t.Fatalf("expected no errors for a valid trait, got %v", byService["valid"])
+	}
+}
+
+func TestJSONTypeMatchesIntegerVsFloat(t *testing.T) {
+	if !jsonTypeMatches("integer", float64(3)) {
+		t.Fatalf("expected 3 to match integer")
+	}
+	if jsonTypeMatches("integer", float64(3.5)) {
+		t.Fatalf("expected 3.5 to not match integer")
+	}
+	if !jsonTypeMatches("number", float64(3.5)) {
+		t.Fatalf("expected 3.5 to match number")
+	}
+}
+
+type fakeComponentRegistry map[string]bool
+
+func (f fakeComponentRegistry) HasComponent(kind string) bool { return f[kind] }
+
+func TestComponentCapabilityValidator(t *testing.T) {
+	p := &Pattern{Services: map[string]*Service{
+		"known":   {Type: "WebService"},
+		"unknown": {Type: "NotRegistered"},
+	}}
+
+	errs := p.Validate(context.Background(), ComponentCapabilityValidator{Registry: fakeComponentRegistry{"WebService": true}})
+
+	if len(errs) != 1 || errs[0].Service != "unknown" {
+		t.Fatalf("expected exactly one error for the unregistered component kind, got %v", errs)
+	}
+}
+
+// warningOnlyValidator is a test double that always reports a single
+// SeverityWarning diagnostic, never SeverityError.
+type warningOnlyValidator struct{}
+
+func (warningOnlyValidator) Validate(_ context.Context, _ *Pattern) []ValidationError {
+	return []ValidationError{{Service: "web", Path: "type", Severity: SeverityWarning, Message: "looks unusual"}}
+}
+
+// blockingValidator is a test double that always reports a single
+// SeverityError diagnostic.
+type blockingValidator struct{}
+
+func (blockingValidator) Validate(_ context.Context, _ *Pattern) []ValidationError {
+	return []ValidationError{{Service: "web", Path: "type", Severity: SeverityError, Message: "not allowed"}}
+}
+
+func TestNewPatternFileValidatorGating(t *testing.T) {
+	yml := []byte("name: test\nservices:\n  web:\n    type: WebService\n")
+
+	if _, err := NewPatternFile(yml, warningOnlyValidator{}); err != nil {
+		t.Fatalf("expected a SeverityWarning to not fail NewPatternFile, got %s", err)
+	}
+
+	if _, err := NewPatternFile(yml, blockingValidator{}); err == nil {
+		t.Fatalf("expected a SeverityError to fail NewPatternFile")
+	}
+
+	if _, err := NewPatternFile(yml); err != nil {
+		t.Fatalf("expected NewPatternFile with no validators to succeed, got %s", err)
+	}
+}